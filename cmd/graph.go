@@ -19,7 +19,7 @@ package cmd
 import (
 	"strconv"
 
-	"github.com/bitnami-labs/kubewatch/config"
+	"github.com/yindia/kubewatch/config"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -88,6 +88,28 @@ var graphConfigCmd = &cobra.Command{
 			logrus.Fatal(err)
 		}
 
+		iamAuth, err := cmd.Flags().GetString("iam-auth")
+		if err == nil {
+			if len(iamAuth) > 0 {
+				isIAMAuth, err := strconv.ParseBool(iamAuth)
+				if err != nil {
+					logrus.Fatal(err)
+				}
+				conf.Handler.Graph.IAMAuth = isIAMAuth
+			}
+		} else {
+			logrus.Fatal(err)
+		}
+
+		backend, err := cmd.Flags().GetString("backend")
+		if err == nil {
+			if len(backend) > 0 {
+				conf.Handler.Graph.Backend = backend
+			}
+		} else {
+			logrus.Fatal(err)
+		}
+
 		tlsSkip, err := cmd.Flags().GetString("tlsskip")
 		if err == nil {
 			if len(tlsSkip) > 0 {
@@ -115,5 +137,7 @@ func init() {
 	graphConfigCmd.Flags().StringP("enabled", "", "", "Enable graph handler; TRUE or FALSE")
 	graphConfigCmd.Flags().StringP("traversal-source", "t", "", "Specify graph traversal source (default: g)")
 	graphConfigCmd.Flags().StringP("timeout", "", "", "Specify connection timeout in seconds (default: 30)")
+	graphConfigCmd.Flags().StringP("backend", "b", "", "Specify graph backend: neptune, janusgraph, cosmosdb, or tinkergraph (default: neptune)")
+	graphConfigCmd.Flags().StringP("iam-auth", "", "", "Enable SigV4 IAM database authentication for Neptune; TRUE or FALSE")
 	graphConfigCmd.Flags().StringP("tlsskip", "", "", "Specify whether to skip TLS verification; TRUE or FALSE")
 }
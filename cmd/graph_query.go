@@ -0,0 +1,93 @@
+/*
+Copyright 2018 Bitnami
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/yindia/kubewatch/config"
+	"github.com/yindia/kubewatch/pkg/handlers/graph"
+)
+
+// graphQueryCmd represents the "kubewatch graph query" subcommand
+var graphQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "run a query against the graph store",
+	Long: `Run a Gremlin, openCypher, or SPARQL statement against the configured
+graph backend and print the results as JSON. Use --canned to run a
+built-in query instead of writing a statement by hand.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		conf, err := config.New()
+		if err != nil {
+			logrus.Fatal(err)
+		}
+
+		language, _ := cmd.Flags().GetString("language")
+		statement, _ := cmd.Flags().GetString("statement")
+		canned, _ := cmd.Flags().GetString("canned")
+		namespace, _ := cmd.Flags().GetString("namespace")
+		resource, _ := cmd.Flags().GetString("resource")
+
+		var bindings map[string]interface{}
+		switch canned {
+		case "recent-warnings":
+			statement, bindings = graph.RecentWarningsQuery(namespace)
+		case "owner-chain":
+			statement, bindings = graph.OwnerChainQuery(resource)
+		case "":
+			// use --statement as given
+		default:
+			logrus.Fatalf("unknown canned query: %s", canned)
+		}
+
+		if statement == "" {
+			logrus.Fatal("one of --statement or --canned must be set")
+		}
+
+		g := &graph.Graph{}
+		if err := g.Init(conf); err != nil {
+			logrus.Fatal(err)
+		}
+		defer g.Close()
+
+		result, err := g.Query(context.Background(), graph.QueryLanguage(language), statement, bindings)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+
+		out, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		fmt.Println(string(out))
+	},
+}
+
+func init() {
+	graphQueryCmd.Flags().StringP("language", "l", "gremlin", "Query language: gremlin, opencypher, or sparql")
+	graphQueryCmd.Flags().StringP("statement", "s", "", "Raw query statement to run")
+	graphQueryCmd.Flags().String("canned", "", "Run a built-in query: recent-warnings, owner-chain")
+	graphQueryCmd.Flags().String("namespace", "", "Namespace argument for the recent-warnings canned query")
+	graphQueryCmd.Flags().String("resource", "", "Resource ID argument for the owner-chain canned query")
+
+	graphConfigCmd.AddCommand(graphQueryCmd)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2016 Skippbox, Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigFileName is the name kubewatch looks for (and writes to) in the
+// user's home directory.
+const ConfigFileName = ".kubewatch.yaml"
+
+// Config stores the whole configuration for kubewatch, as read from and
+// written to ~/.kubewatch.yaml.
+type Config struct {
+	Handler Handler `json:"handler"`
+}
+
+// Handler holds configuration for every event handler kubewatch can
+// dispatch to. Only one is normally enabled at a time.
+type Handler struct {
+	Graph   Graph   `json:"graph"`
+	Tracing Tracing `json:"tracing"`
+}
+
+// New loads the config file from the user's home directory, returning a
+// zero-value Config if it doesn't exist yet.
+func New() (*Config, error) {
+	c := &Config{}
+	if err := c.Load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Load reads the config file into c. A missing config file is not an
+// error: c is left at its zero value so callers can fill it in (e.g. via
+// flags) and Write it back out.
+func (c *Config) Load() error {
+	configFile, err := configFilePath()
+	if err != nil {
+		return err
+	}
+
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	v.SetConfigType("yaml")
+
+	if err := v.ReadInConfig(); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file %s: %w", configFile, err)
+	}
+
+	return v.Unmarshal(c)
+}
+
+// Write persists c to the config file in the user's home directory,
+// creating it if necessary.
+func (c *Config) Write() error {
+	configFile, err := configFilePath()
+	if err != nil {
+		return err
+	}
+
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	v.SetConfigType("yaml")
+
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := v.ReadConfig(bytes.NewReader(b)); err != nil {
+		return fmt.Errorf("failed to load marshaled config: %w", err)
+	}
+
+	return v.WriteConfigAs(configFile)
+}
+
+// configFilePath returns the path to the user's kubewatch config file.
+func configFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ConfigFileName), nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025 Kubewatch Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// Graph holds configuration for the graph event handler
+// (pkg/handlers/graph), which records Kubernetes events as vertices and
+// edges in a Gremlin-speaking graph database.
+type Graph struct {
+	// Enabled turns on the graph handler. Endpoint must also be set.
+	Enabled bool `json:"enabled"`
+	// Endpoint is the Gremlin WebSocket endpoint, e.g.
+	// wss://your-cluster.region.neptune.amazonaws.com:8182/gremlin.
+	// Falls back to KW_GRAPH_ENDPOINT when unset.
+	Endpoint string `json:"endpoint"`
+	// Region is the AWS region of the Neptune cluster. Required for the
+	// neptune backend; ignored by the others. Falls back to
+	// KW_GRAPH_REGION when unset.
+	Region string `json:"region"`
+	// TraversalSource names the remote traversal source. Defaults to "g".
+	TraversalSource string `json:"traversalSource"`
+	// Timeout is the connection timeout, in seconds. Defaults to 30.
+	Timeout int `json:"timeout"`
+	// TlsSkip disables TLS certificate verification, for self-signed or
+	// local backends.
+	TlsSkip bool `json:"tlsSkip"`
+	// Backend selects the graph database implementation: "neptune"
+	// (default), "janusgraph", "cosmosdb", or "tinkergraph".
+	Backend string `json:"backend"`
+	// IAMAuth enables SigV4 IAM database authentication for Neptune.
+	IAMAuth bool `json:"iamAuth"`
+
+	// BatchSize is the maximum number of queued events coalesced into a
+	// single Gremlin round-trip. Defaults to 50; see pkg/handlers/graph/batch.go.
+	BatchSize int `json:"batchSize"`
+	// QueueSize is the capacity of the buffered writer's event queue.
+	QueueSize int `json:"queueSize"`
+	// FlushIntervalSeconds is the maximum time a partial batch waits
+	// before being flushed even if BatchSize hasn't been reached.
+	FlushIntervalSeconds int `json:"flushIntervalSeconds"`
+	// QueuePolicy controls what happens when the queue is full: "block"
+	// (default) waits for room, applying backpressure to the informer;
+	// "drop-oldest" discards the oldest queued event to make room for
+	// the new one instead.
+	QueuePolicy string `json:"queuePolicy"`
+
+	// CosmosDB holds settings specific to the cosmosdb backend.
+	CosmosDB CosmosDB `json:"cosmosDB"`
+}
+
+// CosmosDB holds settings required by Azure Cosmos DB's Gremlin API,
+// which needs a partition-key property set on every vertex.
+type CosmosDB struct {
+	// PartitionKeyProperty is the vertex property name used as the
+	// partition key, e.g. "pk".
+	PartitionKeyProperty string `json:"partitionKeyProperty"`
+	// PartitionKeyValue is the value written to PartitionKeyProperty on
+	// every vertex.
+	PartitionKeyValue string `json:"partitionKeyValue"`
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2025 Kubewatch Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// Tracing holds configuration for OpenTelemetry tracing of event
+// ingestion and graph writes; see pkg/handlers/graph/tracing.go.
+type Tracing struct {
+	// Enabled turns on tracing. When false, InitTracing is a no-op.
+	Enabled bool `json:"enabled"`
+	// Endpoint overrides the OTLP gRPC collector endpoint. When unset,
+	// the exporter falls back to the standard OTEL_EXPORTER_OTLP_ENDPOINT
+	// environment variable.
+	Endpoint string `json:"endpoint"`
+	// Insecure disables TLS on the OTLP gRPC connection, for local
+	// collectors.
+	Insecure bool `json:"insecure"`
+}
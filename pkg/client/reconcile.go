@@ -0,0 +1,134 @@
+/*
+Copyright 2025 Kubewatch Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/yindia/kubewatch/pkg/handlers/graph"
+)
+
+// startGraphReconcile wires g.Reconcile into the running process when
+// reconciliation is configured via KW_GRAPH_RECONCILE_INTERVAL_SECONDS,
+// so tombstoning and pruning of deleted-resource vertices actually runs
+// in the shipped binary instead of sitting unreachable. It is a no-op
+// when that variable is unset, invalid, or <= 0, and logs rather than
+// failing startup if a Kubernetes client can't be built.
+func startGraphReconcile(g *graph.Graph) {
+	intervalSeconds, err := strconv.Atoi(os.Getenv("KW_GRAPH_RECONCILE_INTERVAL_SECONDS"))
+	if err != nil || intervalSeconds <= 0 {
+		return
+	}
+
+	ttlSeconds, err := strconv.Atoi(os.Getenv("KW_GRAPH_RECONCILE_TTL_SECONDS"))
+	if err != nil || ttlSeconds <= 0 {
+		ttlSeconds = intervalSeconds * 10
+	}
+
+	liveIDs, err := newClusterLiveResourceIDs(g)
+	if err != nil {
+		logrus.Errorf("Graph reconcile disabled: %v", err)
+		return
+	}
+
+	g.Reconcile(context.Background(),
+		time.Duration(intervalSeconds)*time.Second,
+		time.Duration(ttlSeconds)*time.Second,
+		liveIDs,
+	)
+}
+
+// newClusterLiveResourceIDs builds a graph.LiveResourceIDsFunc backed by
+// the Kubernetes API: on every call it asks g which (kind, namespace)
+// pairs it currently has recorded, lists the live objects of each via
+// the dynamic client, and returns their "kind:namespace:name" IDs, in
+// the same format Graph.Handle uses for resource vertex IDs.
+func newClusterLiveResourceIDs(g *graph.Graph) (graph.LiveResourceIDsFunc, error) {
+	restConfig, err := clusterRestConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Kubernetes client config: %w", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build discovery client: %w", err)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dynamic client: %w", err)
+	}
+
+	return func(ctx context.Context) ([]string, error) {
+		pairs, err := g.ResourceKindNamespaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var ids []string
+		for _, p := range pairs {
+			mapping, err := mapper.RESTMapping(schema.GroupKind{Kind: p.Kind})
+			if err != nil {
+				logrus.Warnf("Graph reconcile: no REST mapping for kind %q, keeping its vertices out of this tick's tombstone pass: %v", p.Kind, err)
+				existing, existingErr := g.ResourceIDs(ctx, p.Kind, p.Namespace)
+				if existingErr != nil {
+					return nil, fmt.Errorf("failed to preserve existing %s vertices in namespace %s after REST mapping failure: %w", p.Kind, p.Namespace, existingErr)
+				}
+				ids = append(ids, existing...)
+				continue
+			}
+
+			list, err := dynamicClient.Resource(mapping.Resource).Namespace(p.Namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s in namespace %s: %w", p.Kind, p.Namespace, err)
+			}
+			for _, item := range list.Items {
+				ids = append(ids, fmt.Sprintf("%s:%s:%s", p.Kind, p.Namespace, item.GetName()))
+			}
+		}
+		return ids, nil
+	}, nil
+}
+
+// clusterRestConfig resolves a Kubernetes client config the same way
+// kubewatch's own informers do: in-cluster config when running as a pod,
+// falling back to the default kubeconfig loading rules for local
+// development.
+func clusterRestConfig() (*rest.Config, error) {
+	if cfg, err := rest.InClusterConfig(); err == nil {
+		return cfg, nil
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{},
+	).ClientConfig()
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package client
 
 import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"net/http"
 	"os"
+	"strings"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/yindia/kubewatch/config"
@@ -35,18 +39,87 @@ func Run(conf *config.Config) {
 		listenAddress = ":2112"
 	}
 
+	shutdownTracing, err := graph.InitTracing(context.Background(), conf.Handler.Tracing)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	defer shutdownTracing(context.Background())
+
+	var eventHandler = ParseEventHandler(conf)
+
+	if g, ok := eventHandler.(*graph.Graph); ok {
+		startGraphReconcile(g)
+	}
+
 	go func() {
 		http.Handle("/metrics", promhttp.Handler())
+		http.HandleFunc("/graph/query", graphQueryHandler(eventHandler, os.Getenv("KW_GRAPH_QUERY_TOKEN")))
 		logrus.Infof("Starting metrics server on port %s", listenAddress)
 		if err := http.ListenAndServe(listenAddress, nil); err != nil {
 			logrus.Errorf("Error starting metrics server on port %s: %v", listenAddress, err)
 		}
 	}()
 
-	var eventHandler = ParseEventHandler(conf)
 	controller.Start(conf, eventHandler)
 }
 
+// graphQueryHandler serves /graph/query, accepting a "language" and
+// "statement" query parameter and returning the graph.ResultSet as JSON.
+// It responds 404 when the configured event handler is not the graph
+// handler, since there is nothing to query.
+//
+// This endpoint shares the unauthenticated metrics listener, so it is
+// disabled by default: authToken (from KW_GRAPH_QUERY_TOKEN) must be set
+// for it to serve anything, and callers must present it as
+// "Authorization: Bearer <token>". Gremlin statements are additionally
+// required to be read-only, since, unlike the --statement CLI flag,
+// reaching this endpoint only requires network access to the metrics
+// port, not a shell on the host.
+func graphQueryHandler(eventHandler handlers.Handler, authToken string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if authToken == "" {
+			http.Error(w, "graph query endpoint disabled: set KW_GRAPH_QUERY_TOKEN to enable", http.StatusNotFound)
+			return
+		}
+
+		presented := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(authToken)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		g, ok := eventHandler.(*graph.Graph)
+		if !ok {
+			http.Error(w, "graph handler is not enabled", http.StatusNotFound)
+			return
+		}
+
+		language := r.URL.Query().Get("language")
+		statement := r.URL.Query().Get("statement")
+		if statement == "" {
+			http.Error(w, "missing statement query parameter", http.StatusBadRequest)
+			return
+		}
+
+		lang := graph.QueryLanguage(language)
+		if (lang == graph.Gremlin || lang == "") && !graph.IsReadOnlyGremlin(statement) {
+			http.Error(w, "statement must be a read-only traversal over this endpoint; use the graph query CLI for writes", http.StatusForbidden)
+			return
+		}
+
+		result, err := g.Query(r.Context(), lang, statement, nil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			logrus.Errorf("Error encoding graph query response: %v", err)
+		}
+	}
+}
+
 // ParseEventHandler returns the respective handler object specified in the config file.
 func ParseEventHandler(conf *config.Config) handlers.Handler {
 
@@ -0,0 +1,220 @@
+/*
+Copyright 2016 Skippbox, Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controller watches Kubernetes resources via dynamic informers
+// and dispatches every add/update/delete to the configured
+// handlers.Handler as an event.Event.
+package controller
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/yindia/kubewatch/config"
+	"github.com/yindia/kubewatch/pkg/event"
+	"github.com/yindia/kubewatch/pkg/handlers"
+)
+
+// podGVR is resolved directly, rather than through discovery, since
+// watcher.selectedPods looks pods up by it on every Service event.
+var podGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+// watchedKinds are the Kubernetes kinds kubewatch's informer path
+// watches and turns into event.Event. Pod, Service, and
+// PersistentVolumeClaim additionally populate the topology fields
+// pkg/handlers/graph uses to materialize SCHEDULED_ON, SELECTS, and
+// MOUNTS edges; the others only ever carry OwnerReferences.
+var watchedKinds = []string{
+	"Pod", "Service", "PersistentVolumeClaim",
+	"Deployment", "ReplicaSet", "StatefulSet", "DaemonSet",
+}
+
+// Start builds a Kubernetes client from the in-cluster config (falling
+// back to the local kubeconfig), starts a dynamic informer per kind in
+// watchedKinds, and dispatches every add/update/delete it observes to
+// handler.Handle. It blocks until the process exits.
+func Start(conf *config.Config, handler handlers.Handler) {
+	restConfig, err := restConfigFromEnv()
+	if err != nil {
+		logrus.Fatalf("Controller: failed to build Kubernetes client config: %v", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		logrus.Fatalf("Controller: failed to build discovery client: %v", err)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		logrus.Fatalf("Controller: failed to build dynamic client: %v", err)
+	}
+
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0)
+	w := &watcher{dynamicClient: dynamicClient, handler: handler}
+
+	for _, kind := range watchedKinds {
+		mapping, err := mapper.RESTMapping(schema.GroupKind{Kind: kind})
+		if err != nil {
+			logrus.Warnf("Controller: no REST mapping for kind %q, skipping: %v", kind, err)
+			continue
+		}
+
+		informer := factory.ForResource(mapping.Resource).Informer()
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { w.handle(obj, "create") },
+			UpdateFunc: func(_, obj interface{}) { w.handle(obj, "update") },
+			DeleteFunc: func(obj interface{}) { w.handle(obj, "delete") },
+		})
+	}
+
+	stopCh := make(chan struct{})
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+
+	logrus.Info("Controller: informers started, watching for events")
+	<-stopCh
+}
+
+// watcher converts informer callbacks into event.Event and forwards them
+// to the configured handler.
+type watcher struct {
+	dynamicClient dynamic.Interface
+	handler       handlers.Handler
+}
+
+// handle builds an event.Event from obj and dispatches it. Unexpected
+// informer payloads are silently dropped rather than panicking, since a
+// malformed event should never take down the watch loop.
+func (w *watcher) handle(obj interface{}, action string) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	e := event.Event{
+		Kind:      u.GetKind(),
+		Name:      u.GetName(),
+		Namespace: u.GetNamespace(),
+		Reason:    reasonForAction(action),
+		Action:    action,
+	}
+
+	for _, ref := range u.GetOwnerReferences() {
+		e.OwnerReferences = append(e.OwnerReferences, event.OwnerReference{Kind: ref.Kind, Name: ref.Name})
+	}
+
+	switch e.Kind {
+	case "Pod":
+		e.NodeName, _, _ = unstructured.NestedString(u.Object, "spec", "nodeName")
+		e.VolumeClaims = podVolumeClaims(u)
+	case "Service":
+		e.SelectedPods = w.selectedPods(u)
+	case "PersistentVolumeClaim":
+		e.VolumeName, _, _ = unstructured.NestedString(u.Object, "spec", "volumeName")
+	}
+
+	w.handler.Handle(e)
+}
+
+// selectedPods lists the pods in svc's namespace matching its selector,
+// for the Service->Pod SELECTS edge. It returns nil (rather than erroring
+// the whole event) if svc has no selector or the list call fails, since a
+// missing topology edge is preferable to dropping the event entirely.
+func (w *watcher) selectedPods(svc *unstructured.Unstructured) []string {
+	selector, found, err := unstructured.NestedStringMap(svc.Object, "spec", "selector")
+	if err != nil || !found || len(selector) == 0 {
+		return nil
+	}
+
+	pods, err := w.dynamicClient.Resource(podGVR).Namespace(svc.GetNamespace()).
+		List(context.Background(), metav1.ListOptions{LabelSelector: labels.SelectorFromSet(selector).String()})
+	if err != nil {
+		logrus.Warnf("Controller: failed to list pods selected by Service %s/%s: %v", svc.GetNamespace(), svc.GetName(), err)
+		return nil
+	}
+
+	names := make([]string, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		names = append(names, pod.GetName())
+	}
+	return names
+}
+
+// podVolumeClaims returns the claimName of every PersistentVolumeClaim
+// volume mounted by pod, for the Pod->PVC MOUNTS edge.
+func podVolumeClaims(pod *unstructured.Unstructured) []string {
+	volumes, found, err := unstructured.NestedSlice(pod.Object, "spec", "volumes")
+	if err != nil || !found {
+		return nil
+	}
+
+	var claims []string
+	for _, v := range volumes {
+		vol, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if claimName, found, err := unstructured.NestedString(vol, "persistentVolumeClaim", "claimName"); err == nil && found {
+			claims = append(claims, claimName)
+		}
+	}
+	return claims
+}
+
+// reasonForAction maps an informer action to the human-readable Reason
+// kubewatch's handlers have always reported.
+func reasonForAction(action string) string {
+	switch action {
+	case "create":
+		return "Created"
+	case "update":
+		return "Updated"
+	case "delete":
+		return "Deleted"
+	default:
+		return ""
+	}
+}
+
+// restConfigFromEnv resolves a Kubernetes client config the same way
+// pkg/client.clusterRestConfig does: in-cluster config when running as a
+// pod, falling back to the default kubeconfig loading rules for local
+// development. It is duplicated rather than shared because pkg/client
+// already depends on this package, and this package must not depend back
+// on pkg/client.
+func restConfigFromEnv() (*rest.Config, error) {
+	if cfg, err := rest.InClusterConfig(); err == nil {
+		return cfg, nil
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{},
+	).ClientConfig()
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2016 Skippbox, Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package event defines the resource-change notification kubewatch's
+// informer path (pkg/controller) produces and every handler
+// (pkg/handlers) consumes.
+package event
+
+import "fmt"
+
+// OwnerReference identifies an owner of a Kubernetes object,
+// mirroring the subset of metav1.OwnerReference the graph handler needs
+// to materialize OWNED_BY edges (see pkg/handlers/graph/topology.go).
+type OwnerReference struct {
+	Kind string
+	Name string
+}
+
+// Event represents a single Kubernetes resource add, update, or delete as
+// observed by an informer in pkg/controller.
+type Event struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Reason    string
+	Action    string
+
+	// Topology fields, populated by pkg/controller for the kinds that
+	// carry them. pkg/handlers/graph/topology.go consumes these to
+	// materialize OWNED_BY, SCHEDULED_ON, SELECTS, and MOUNTS edges;
+	// they are the zero value for kinds that don't apply.
+	OwnerReferences []OwnerReference
+	NodeName        string
+	SelectedPods    []string
+	VolumeClaims    []string
+	VolumeName      string
+}
+
+// Message returns a short human-readable summary of the event, used as
+// the "message" property on graph Event vertices.
+func (e Event) Message() string {
+	return fmt.Sprintf("%s %s %s/%s", e.Reason, e.Kind, e.Namespace, e.Name)
+}
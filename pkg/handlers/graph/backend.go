@@ -0,0 +1,172 @@
+/*
+Copyright 2025 Kubewatch Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/yindia/kubewatch/config"
+)
+
+// BackendKind names a Gremlin-speaking graph database the handler can
+// target. The wire protocol is shared across all of them; only connection
+// setup and a handful of per-vertex requirements differ.
+type BackendKind string
+
+const (
+	// BackendNeptune is Amazon Neptune, the default for back-compat.
+	BackendNeptune BackendKind = "neptune"
+	// BackendJanusGraph is a self-hosted JanusGraph cluster.
+	BackendJanusGraph BackendKind = "janusgraph"
+	// BackendCosmosDB is Azure Cosmos DB's Gremlin API, which requires a
+	// partition-key property on every vertex.
+	BackendCosmosDB BackendKind = "cosmosdb"
+	// BackendTinkerGraph points at a local gremlin-server instance
+	// backed by TinkerGraph, for development and CI without a cloud
+	// dependency.
+	BackendTinkerGraph BackendKind = "tinkergraph"
+)
+
+// Backend customizes connection setup and vertex writes for a specific
+// Gremlin server implementation.
+type Backend interface {
+	// Kind identifies the backend for logging and config round-tripping.
+	Kind() BackendKind
+	// Headers returns extra HTTP headers to send on the WebSocket
+	// handshake, or nil if none are needed. It is called fresh on every
+	// (re)connect, since e.g. Neptune IAM auth signatures expire.
+	Headers(ctx context.Context, endpoint string) (http.Header, error)
+	// SignHTTPRequest signs req in place for backends whose HTTP query
+	// endpoints (openCypher, SPARQL; see query_http.go) require
+	// per-request authentication, e.g. Neptune IAM database auth. It is
+	// a no-op for backends that don't.
+	SignHTTPRequest(ctx context.Context, req *http.Request) error
+	// VertexProperties returns extra properties this backend requires on
+	// every vertex written, e.g. CosmosDB's partition key.
+	VertexProperties() map[string]interface{}
+}
+
+// newBackend constructs the Backend named by kind, defaulting to Neptune
+// when kind is empty so existing configs keep working unchanged.
+func newBackend(kind BackendKind, cfg config.Graph) (Backend, error) {
+	switch kind {
+	case "", BackendNeptune:
+		nb := &neptuneBackend{}
+		if cfg.IAMAuth {
+			signer, err := newNeptuneIAMSigner(cfg.Region)
+			if err != nil {
+				return nil, err
+			}
+			nb.signer = signer
+		}
+		return nb, nil
+	case BackendJanusGraph:
+		return &janusGraphBackend{}, nil
+	case BackendCosmosDB:
+		if cfg.CosmosDB.PartitionKeyProperty == "" || cfg.CosmosDB.PartitionKeyValue == "" {
+			return nil, fmt.Errorf("cosmosdb backend requires Handler.Graph.CosmosDB.PartitionKeyProperty and PartitionKeyValue")
+		}
+		return &cosmosDBBackend{
+			partitionKeyProperty: cfg.CosmosDB.PartitionKeyProperty,
+			partitionKeyValue:    cfg.CosmosDB.PartitionKeyValue,
+		}, nil
+	case BackendTinkerGraph:
+		return &tinkerGraphBackend{}, nil
+	default:
+		return nil, fmt.Errorf("unknown graph backend: %s", kind)
+	}
+}
+
+// neptuneBackend targets Amazon Neptune. When IAM database authentication
+// is enabled, signer computes fresh SigV4 headers on every connect; see
+// sigv4.go.
+type neptuneBackend struct {
+	signer *neptuneIAMSigner
+}
+
+func (b *neptuneBackend) Kind() BackendKind { return BackendNeptune }
+
+func (b *neptuneBackend) Headers(ctx context.Context, endpoint string) (http.Header, error) {
+	if b.signer == nil {
+		return nil, nil
+	}
+	return b.signer.Headers(ctx, endpoint)
+}
+
+func (b *neptuneBackend) SignHTTPRequest(ctx context.Context, req *http.Request) error {
+	if b.signer == nil {
+		return nil
+	}
+	return b.signer.Sign(ctx, req)
+}
+
+func (b *neptuneBackend) VertexProperties() map[string]interface{} { return nil }
+
+// janusGraphBackend targets a self-hosted JanusGraph cluster speaking
+// plain Gremlin, with no vendor-specific requirements.
+type janusGraphBackend struct{}
+
+func (b *janusGraphBackend) Kind() BackendKind { return BackendJanusGraph }
+
+func (b *janusGraphBackend) Headers(ctx context.Context, endpoint string) (http.Header, error) {
+	return nil, nil
+}
+
+func (b *janusGraphBackend) SignHTTPRequest(ctx context.Context, req *http.Request) error { return nil }
+
+func (b *janusGraphBackend) VertexProperties() map[string]interface{} { return nil }
+
+// cosmosDBBackend targets Azure Cosmos DB's Gremlin API, which requires a
+// partition-key property to be set on every vertex at creation time.
+type cosmosDBBackend struct {
+	partitionKeyProperty string
+	partitionKeyValue    string
+}
+
+func (b *cosmosDBBackend) Kind() BackendKind { return BackendCosmosDB }
+
+func (b *cosmosDBBackend) Headers(ctx context.Context, endpoint string) (http.Header, error) {
+	return nil, nil
+}
+
+func (b *cosmosDBBackend) SignHTTPRequest(ctx context.Context, req *http.Request) error { return nil }
+
+func (b *cosmosDBBackend) VertexProperties() map[string]interface{} {
+	return map[string]interface{}{b.partitionKeyProperty: b.partitionKeyValue}
+}
+
+// tinkerGraphBackend targets a local gremlin-server process backed by
+// TinkerGraph (e.g. `docker run -p 8182:8182 tinkerpop/gremlin-server`),
+// so local development, CI, and tests can exercise the handler without
+// Neptune or any other cloud dependency. This Go Gremlin client is
+// protocol-only, so, unlike its name might suggest, it is not an
+// in-process graph engine: a gremlin-server process backed by
+// TinkerGraph still has to be running on the other end of the
+// connection. See TestTinkerGraphBackend in graph_test.go.
+type tinkerGraphBackend struct{}
+
+func (b *tinkerGraphBackend) Kind() BackendKind { return BackendTinkerGraph }
+
+func (b *tinkerGraphBackend) Headers(ctx context.Context, endpoint string) (http.Header, error) {
+	return nil, nil
+}
+
+func (b *tinkerGraphBackend) SignHTTPRequest(ctx context.Context, req *http.Request) error { return nil }
+
+func (b *tinkerGraphBackend) VertexProperties() map[string]interface{} { return nil }
@@ -0,0 +1,273 @@
+/*
+Copyright 2025 Kubewatch Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yindia/kubewatch/pkg/event"
+)
+
+// QueuePolicy controls what happens when the buffered writer's queue is
+// full.
+type QueuePolicy string
+
+const (
+	// QueuePolicyBlock blocks Handle until room is available, applying
+	// backpressure to the informer that calls it.
+	QueuePolicyBlock QueuePolicy = "block"
+	// QueuePolicyDropOldest discards the oldest queued event to make
+	// room for the new one, favoring freshness over completeness.
+	QueuePolicyDropOldest QueuePolicy = "drop-oldest"
+)
+
+const (
+	defaultBatchSize     = 50
+	defaultFlushInterval = 5 * time.Second
+	defaultQueueSize     = 1000
+)
+
+var (
+	graphEventsWrittenTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "graph_events_written_total",
+		Help: "Total number of events successfully written to the graph.",
+	})
+	graphEventsDroppedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "graph_events_dropped_total",
+		Help: "Total number of events dropped before being written to the graph.",
+	})
+	graphBatchFlushDurationSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name: "graph_batch_flush_duration_seconds",
+		Help: "Time taken to flush a batch of events to the graph.",
+	})
+)
+
+// queuedEvent pairs an event with the eventID it will be written under, so
+// the ID is computed exactly once and shared by the node and the edge that
+// references it. ctx carries the "k8s.event" span opened in Handle, which
+// stays open until flush finishes writing (or dropping) the event.
+type queuedEvent struct {
+	event   event.Event
+	eventID string
+	ctx     context.Context
+}
+
+// startBatcher configures batching parameters from c.Handler.Graph,
+// allocates the queue, and starts the background flush loop. It must be
+// called after connect() so the batcher has a live client to flush
+// through.
+func (g *Graph) startBatcher(batchSize, queueSize int, flushInterval time.Duration, policy QueuePolicy) {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+	if policy == "" {
+		policy = QueuePolicyBlock
+	}
+
+	g.batchSize = batchSize
+	g.flushInterval = flushInterval
+	g.queuePolicy = policy
+	g.queue = make(chan queuedEvent, queueSize)
+	g.stopCh = make(chan struct{})
+
+	g.wg.Add(1)
+	go g.runBatcher()
+}
+
+// enqueue hands an event to the batcher, applying the configured queue
+// policy when the buffer is full.
+func (g *Graph) enqueue(qe queuedEvent) {
+	if g.queuePolicy == QueuePolicyDropOldest {
+		select {
+		case g.queue <- qe:
+		default:
+			select {
+			case <-g.queue:
+				graphEventsDroppedTotal.Inc()
+			default:
+			}
+			select {
+			case g.queue <- qe:
+			default:
+				graphEventsDroppedTotal.Inc()
+			}
+		}
+		return
+	}
+
+	g.queue <- qe
+}
+
+// runBatcher coalesces queued events into batches of up to g.batchSize,
+// flushing whenever a batch fills up or g.flushInterval elapses, whichever
+// comes first.
+func (g *Graph) runBatcher() {
+	defer g.wg.Done()
+
+	ticker := time.NewTicker(g.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]queuedEvent, 0, g.batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		g.flush(batch)
+		batch = make([]queuedEvent, 0, g.batchSize)
+	}
+
+	for {
+		select {
+		case qe := <-g.queue:
+			batch = append(batch, qe)
+			if len(batch) >= g.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-g.stopCh:
+			// Drain whatever is already queued before exiting.
+			for {
+				select {
+				case qe := <-g.queue:
+					batch = append(batch, qe)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush writes a batch of events to the graph as a single traversal,
+// upserting each resource node with coalesce(V(id), addV(...)) semantics
+// and adding each event node and its HAS_EVENT edge. Each event's
+// createResourceNode/createEventNode/createEventRelationship steps are
+// traced as child spans of that event's "k8s.event" span, which flush
+// closes once the batch's outcome is known.
+func (g *Graph) flush(batch []queuedEvent) {
+	start := time.Now()
+	defer func() {
+		graphBatchFlushDurationSeconds.Observe(time.Since(start).Seconds())
+	}()
+
+	_, flushSpan := tracer.Start(context.Background(), "graph.flush",
+		trace.WithAttributes(attribute.Int("batch.size", len(batch))))
+	defer flushSpan.End()
+
+	now := time.Now().Unix()
+
+	g.connMu.RLock()
+	traversal := g.g.Inject(0)
+	g.connMu.RUnlock()
+
+	for _, qe := range batch {
+		e := qe.event
+		resourceID := fmt.Sprintf("%s:%s:%s", e.Kind, e.Namespace, e.Name)
+
+		_, resourceSpan := tracer.Start(qe.ctx, "graph.createResourceNode")
+		traversal = traversal.SideEffect(
+			gremlingo.T__.Coalesce(
+				gremlingo.T__.V(resourceID).
+					Property("lastUpdated", now),
+				g.decorateVertex(gremlingo.T__.AddV("Resource").
+					Property("id", resourceID).
+					Property("kind", e.Kind).
+					Property("name", e.Name).
+					Property("namespace", e.Namespace).
+					Property("createdAt", now).
+					Property("lastUpdated", now)),
+			),
+		)
+		resourceSpan.End()
+
+		_, eventSpan := tracer.Start(qe.ctx, "graph.createEventNode")
+		traversal = traversal.SideEffect(
+			g.decorateVertex(gremlingo.T__.AddV("Event").
+				Property("id", qe.eventID).
+				Property("kind", e.Kind).
+				Property("name", e.Name).
+				Property("namespace", e.Namespace).
+				Property("reason", e.Reason).
+				Property("message", e.Message()).
+				Property("timestamp", now)),
+		)
+		eventSpan.End()
+
+		_, edgeSpan := tracer.Start(qe.ctx, "graph.createEventRelationship")
+		traversal = traversal.SideEffect(
+			gremlingo.T__.V(resourceID).
+				AddE("HAS_EVENT").
+				To(gremlingo.T__.V(qe.eventID)).
+				Property("timestamp", now),
+		)
+		edgeSpan.End()
+
+		traversal = g.appendTopologyEdges(traversal, resourceID, e)
+	}
+
+	if _, err := traversal.Next(); err != nil {
+		logrus.Errorf("Failed to flush batch of %d events to graph: %v", len(batch), err)
+		graphEventsDroppedTotal.Add(float64(len(batch)))
+		flushSpan.RecordError(err)
+		flushSpan.SetStatus(codes.Error, err.Error())
+		endEventSpans(batch, codes.Error, err.Error())
+		return
+	}
+
+	graphEventsWrittenTotal.Add(float64(len(batch)))
+	logrus.Printf("Successfully flushed batch of %d events to graph", len(batch))
+	endEventSpans(batch, codes.Ok, "")
+}
+
+// endEventSpans closes the "k8s.event" span carried by each queued
+// event's context, recording the batch's outcome on every one of them.
+func endEventSpans(batch []queuedEvent, status codes.Code, description string) {
+	for _, qe := range batch {
+		span := trace.SpanFromContext(qe.ctx)
+		span.SetStatus(status, description)
+		span.End()
+	}
+}
+
+// stopBatcher signals the flush loop to drain the queue and exit, and
+// waits for it to finish.
+func (g *Graph) stopBatcher() {
+	if g.stopCh == nil {
+		return
+	}
+	close(g.stopCh)
+	g.wg.Wait()
+}
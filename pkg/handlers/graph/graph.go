@@ -17,13 +17,17 @@ limitations under the License.
 package graph
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/yindia/kubewatch/config"
 	"github.com/yindia/kubewatch/pkg/event"
@@ -49,8 +53,23 @@ type Graph struct {
 	traversalSource string
 	timeout         time.Duration
 	tlsSkip         bool
-	connection      *gremlingo.DriverRemoteConnection
-	g               *gremlingo.GraphTraversalSource
+
+	// connMu guards connection, g, and client, which reconnect()
+	// replaces in place while the batcher and query paths may be
+	// reading them concurrently.
+	connMu     sync.RWMutex
+	connection *gremlingo.DriverRemoteConnection
+	g          *gremlingo.GraphTraversalSource
+	client     *gremlingo.Client
+	backend    Backend
+
+	// Buffered writer state; see batch.go.
+	batchSize     int
+	flushInterval time.Duration
+	queuePolicy   QueuePolicy
+	queue         chan queuedEvent
+	stopCh        chan struct{}
+	wg            sync.WaitGroup
 }
 
 // Init prepares Graph handler configuration
@@ -83,150 +102,204 @@ func (g *Graph) Init(c *config.Config) error {
 	g.timeout = time.Duration(timeout) * time.Second
 	g.tlsSkip = tlsSkip
 
+	// Pass the resolved region (including the KW_GRAPH_REGION fallback)
+	// so backend-side signing uses the same region as validateConfig.
+	backendCfg := c.Handler.Graph
+	backendCfg.Region = region
+	backend, err := newBackend(BackendKind(backendCfg.Backend), backendCfg)
+	if err != nil {
+		return err
+	}
+	g.backend = backend
+
 	// Validate required fields
 	if err := g.validateConfig(); err != nil {
 		return err
 	}
 
 	// Initialize Neptune connection
-	return g.connect()
+	if err := g.connect(); err != nil {
+		return err
+	}
+
+	// Start the buffered writer that coalesces events into batches.
+	g.startBatcher(
+		c.Handler.Graph.BatchSize,
+		c.Handler.Graph.QueueSize,
+		time.Duration(c.Handler.Graph.FlushIntervalSeconds)*time.Second,
+		QueuePolicy(c.Handler.Graph.QueuePolicy),
+	)
+
+	// Periodically verify the connection is healthy and rebuild it via
+	// connect() on failure, so backend.Headers() (e.g. Neptune's SigV4
+	// signer) recomputes fresh connection headers instead of a
+	// long-lived connection outliving the ones computed here at Init.
+	g.wg.Add(1)
+	go g.keepConnectionFresh()
+
+	return nil
 }
 
-// validateConfig validates the Graph handler configuration
+// validateConfig validates the Graph handler configuration. The AWS
+// region is only required for the Neptune backend; other backends don't
+// use it.
 func (g *Graph) validateConfig() error {
 	if g.endpoint == "" {
 		return fmt.Errorf(graphErrMsg, "Missing Neptune endpoint")
 	}
-	if g.region == "" {
+	if g.backend.Kind() == BackendNeptune && g.region == "" {
 		return fmt.Errorf(graphErrMsg, "Missing AWS region")
 	}
 	return nil
 }
 
-// connect establishes connection to Neptune
+// connect establishes the backend connection. Called fresh on every
+// (re)connect so backend-issued auth headers, such as Neptune's SigV4
+// signature, never go stale.
 func (g *Graph) connect() error {
+	headers, err := g.backend.Headers(context.Background(), g.endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to build connection headers: %v", err)
+	}
+
 	// Create connection settings
 	settings := func(settings *gremlingo.DriverRemoteConnectionSettings) {
 		settings.TraversalSource = g.traversalSource
 		if g.tlsSkip {
 			settings.TlsConfig = &tls.Config{InsecureSkipVerify: true}
 		}
+		if headers != nil {
+			settings.HandshakeHeaders = headers
+		}
 	}
 
 	// Create the connection
 	connection, err := gremlingo.NewDriverRemoteConnection(g.endpoint, settings)
 	if err != nil {
-		return fmt.Errorf("failed to connect to Neptune: %v", err)
+		return fmt.Errorf("failed to connect to %s: %v", g.backend.Kind(), err)
+	}
+
+	client, err := gremlingo.NewClient(g.endpoint, settings)
+	if err != nil {
+		connection.Close()
+		return fmt.Errorf("failed to create Gremlin client: %v", err)
 	}
 
+	g.connMu.Lock()
 	g.connection = connection
 	g.g = gremlingo.Traversal_().WithRemote(connection)
+	g.client = client
+	g.connMu.Unlock()
 
-	logrus.Printf("Successfully connected to Neptune at %s", g.endpoint)
+	logrus.Printf("Successfully connected to %s at %s", g.backend.Kind(), g.endpoint)
 	return nil
 }
 
-// Handle handles an event by creating nodes and relationships in the graph
-func (g *Graph) Handle(e event.Event) {
-	if g.g == nil {
-		logrus.Error("Graph handler not properly initialized")
-		return
+// reconnect tears down the existing connection and client and
+// re-establishes them via connect(), which asks the backend for fresh
+// connection headers (e.g. a newly computed SigV4 signature) rather than
+// reusing the ones computed at Init or a prior reconnect. It is called by
+// keepConnectionFresh after a failed health check.
+func (g *Graph) reconnect() error {
+	g.connMu.RLock()
+	oldConnection := g.connection
+	oldClient := g.client
+	g.connMu.RUnlock()
+
+	if err := g.connect(); err != nil {
+		return err
 	}
 
-	// Create resource node
-	err := g.createResourceNode(e)
-	if err != nil {
-		logrus.Errorf("Failed to create resource node: %v", err)
-		return
+	if oldClient != nil {
+		oldClient.Close()
 	}
-
-	// Create event node
-	err = g.createEventNode(e)
-	if err != nil {
-		logrus.Errorf("Failed to create event node: %v", err)
-		return
+	if oldConnection != nil {
+		oldConnection.Close()
 	}
+	return nil
+}
 
-	// Create relationship between resource and event
-	err = g.createEventRelationship(e)
-	if err != nil {
-		logrus.Errorf("Failed to create event relationship: %v", err)
-		return
+// connectionHealthCheckInterval is how often keepConnectionFresh probes
+// the connection.
+const connectionHealthCheckInterval = 5 * time.Minute
+
+// keepConnectionFresh runs a background loop that periodically probes
+// the connection with a trivial traversal and reconnects on failure. A
+// driver's own internal reconnect logic, left unchecked, would silently
+// keep reusing the connection headers computed at Init (or the last
+// reconnect) forever; this loop is what actually gives backend.Headers()
+// a chance to recompute a fresh SigV4 signature on a long-lived Graph
+// handler. It stops when g.stopCh is closed; see Close.
+func (g *Graph) keepConnectionFresh() {
+	defer g.wg.Done()
+
+	ticker := time.NewTicker(connectionHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-g.stopCh:
+			return
+		case <-ticker.C:
+			g.connMu.RLock()
+			client := g.client
+			g.connMu.RUnlock()
+
+			if _, err := client.Submit("g.inject(1)"); err != nil {
+				logrus.Warnf("Graph: connection health check failed, reconnecting to refresh backend auth: %v", err)
+				if err := g.reconnect(); err != nil {
+					logrus.Errorf("Graph: reconnect failed: %v", err)
+				}
+			}
+		}
 	}
-
-	logrus.Printf("Successfully stored event in graph: %s/%s", e.Namespace, e.Name)
 }
 
-// createResourceNode creates or updates a Kubernetes resource node
-func (g *Graph) createResourceNode(e event.Event) error {
-	resourceID := fmt.Sprintf("%s:%s:%s", e.Kind, e.Namespace, e.Name)
-	
-	// Check if node exists, create if not
-	exists, err := g.g.V().HasId(resourceID).HasNext()
-	if err != nil {
-		return err
+// decorateVertex appends any extra properties the configured backend
+// requires on every vertex (e.g. CosmosDB's partition key) to an
+// anonymous AddV traversal.
+func (g *Graph) decorateVertex(t *gremlingo.GraphTraversal) *gremlingo.GraphTraversal {
+	for k, v := range g.backend.VertexProperties() {
+		t = t.Property(k, v)
 	}
+	return t
+}
 
-	if !exists {
-		// Create new resource node
-		_, err = g.g.AddV("Resource").
-			Property("id", resourceID).
-			Property("kind", e.Kind).
-			Property("name", e.Name).
-			Property("namespace", e.Namespace).
-			Property("createdAt", time.Now().Unix()).
-			Property("lastUpdated", time.Now().Unix()).
-			Next()
-		if err != nil {
-			return err
-		}
-	} else {
-		// Update existing node
-		_, err = g.g.V(resourceID).
-			Property("lastUpdated", time.Now().Unix()).
-			Next()
-		if err != nil {
-			return err
-		}
+// Handle enqueues an event for the buffered writer, which coalesces it
+// with other pending events into a batch rather than issuing a Gremlin
+// round-trip per event. It returns as soon as the event is queued (or,
+// under QueuePolicyBlock, once room is available).
+//
+// Handle opens the "k8s.event" span for e; it stays open until the event
+// is actually written (or dropped) by flush, so span duration reflects
+// the full informer-to-graph latency, not just time spent queued.
+func (g *Graph) Handle(e event.Event) {
+	if g.g == nil || g.queue == nil {
+		logrus.Error("Graph handler not properly initialized")
+		return
 	}
 
-	return nil
-}
+	ctx, _ := tracer.Start(context.Background(), "k8s.event",
+		trace.WithAttributes(
+			attribute.String("k8s.kind", e.Kind),
+			attribute.String("k8s.namespace", e.Namespace),
+			attribute.String("k8s.name", e.Name),
+			attribute.String("event.reason", e.Reason),
+		),
+	)
 
-// createEventNode creates an event node
-func (g *Graph) createEventNode(e event.Event) error {
 	eventID := fmt.Sprintf("event:%s:%d", e.Name, time.Now().UnixNano())
-	
-	_, err := g.g.AddV("Event").
-		Property("id", eventID).
-		Property("kind", e.Kind).
-		Property("name", e.Name).
-		Property("namespace", e.Namespace).
-		Property("reason", e.Reason).
-		Property("message", e.Message()).
-		Property("timestamp", time.Now().Unix()).
-		Next()
-	
-	return err
+	g.enqueue(queuedEvent{event: e, eventID: eventID, ctx: ctx})
 }
 
-// createEventRelationship creates an edge between resource and event
-func (g *Graph) createEventRelationship(e event.Event) error {
-	resourceID := fmt.Sprintf("%s:%s:%s", e.Kind, e.Namespace, e.Name)
-	eventID := fmt.Sprintf("event:%s:%d", e.Name, time.Now().UnixNano())
-	
-	// Create edge from resource to event
-	_, err := g.g.V(resourceID).
-		AddE("HAS_EVENT").
-		To(gremlingo.T__.V(eventID)).
-		Property("timestamp", time.Now().Unix()).
-		Next()
-	
-	return err
-}
-
-// Close closes the Neptune connection
+// Close stops the buffered writer, flushing any events still queued,
+// then closes the backend's Gremlin client and remote connection.
 func (g *Graph) Close() error {
+	g.stopBatcher()
+
+	if g.client != nil {
+		g.client.Close()
+	}
 	if g.connection != nil {
 		g.connection.Close()
 	}
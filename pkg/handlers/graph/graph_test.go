@@ -17,7 +17,9 @@ limitations under the License.
 package graph
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
 	"reflect"
 	"testing"
@@ -27,6 +29,11 @@ import (
 	"github.com/yindia/kubewatch/pkg/event"
 )
 
+// tinkerGraphTestEndpoint is the local gremlin-server address
+// TestTinkerGraphBackend probes. Start one with, e.g.,
+// `docker run -p 8182:8182 tinkerpop/gremlin-server`.
+const tinkerGraphTestEndpoint = "ws://localhost:8182/gremlin"
+
 func TestGraphInit(t *testing.T) {
 	g := &Graph{}
 	expectedError := fmt.Errorf(graphErrMsg, "Missing Neptune endpoint")
@@ -44,7 +51,7 @@ func TestGraphInit(t *testing.T) {
 				Region:   "us-east-1",
 			},
 			envs: map[string]string{},
-			err:  fmt.Errorf("failed to connect to Neptune: failed to create connection for address test.neptune.amazonaws.com:8182: dial tcp: lookup test.neptune.amazonaws.com: no such host"),
+			err:  fmt.Errorf("failed to connect to neptune: failed to create connection for address test.neptune.amazonaws.com:8182: dial tcp: lookup test.neptune.amazonaws.com: no such host"),
 		},
 		{
 			name:  "Missing endpoint",
@@ -65,7 +72,7 @@ func TestGraphInit(t *testing.T) {
 				"KW_GRAPH_ENDPOINT": "wss://test.neptune.amazonaws.com:8182/gremlin",
 				"KW_GRAPH_REGION":   "us-east-1",
 			},
-			err: fmt.Errorf("failed to connect to Neptune: failed to create connection for address test.neptune.amazonaws.com:8182: dial tcp: lookup test.neptune.amazonaws.com: no such host"),
+			err: fmt.Errorf("failed to connect to neptune: failed to create connection for address test.neptune.amazonaws.com:8182: dial tcp: lookup test.neptune.amazonaws.com: no such host"),
 		},
 		{
 			name: "With traversal source and timeout",
@@ -76,7 +83,26 @@ func TestGraphInit(t *testing.T) {
 				Timeout:         60,
 			},
 			envs: map[string]string{},
-			err:  fmt.Errorf("failed to connect to Neptune: failed to create connection for address test.neptune.amazonaws.com:8182: dial tcp: lookup test.neptune.amazonaws.com: no such host"),
+			err:  fmt.Errorf("failed to connect to neptune: failed to create connection for address test.neptune.amazonaws.com:8182: dial tcp: lookup test.neptune.amazonaws.com: no such host"),
+		},
+		{
+			name: "CosmosDB backend requires partition key config",
+			graph: config.Graph{
+				Endpoint: "wss://test.gremlin.cosmos.azure.com:443/",
+				Backend:  string(BackendCosmosDB),
+			},
+			envs: map[string]string{},
+			err:  fmt.Errorf("cosmosdb backend requires Handler.Graph.CosmosDB.PartitionKeyProperty and PartitionKeyValue"),
+		},
+		{
+			name: "Unknown backend",
+			graph: config.Graph{
+				Endpoint: "wss://test.neptune.amazonaws.com:8182/gremlin",
+				Region:   "us-east-1",
+				Backend:  "dgraph",
+			},
+			envs: map[string]string{},
+			err:  fmt.Errorf("unknown graph backend: dgraph"),
 		},
 	}
 
@@ -112,6 +138,7 @@ func TestValidateConfig(t *testing.T) {
 		name     string
 		endpoint string
 		region   string
+		backend  Backend
 		wantErr  bool
 		errMsg   string
 	}{
@@ -119,12 +146,14 @@ func TestValidateConfig(t *testing.T) {
 			name:     "Valid configuration",
 			endpoint: "wss://test.neptune.amazonaws.com:8182/gremlin",
 			region:   "us-east-1",
+			backend:  &neptuneBackend{},
 			wantErr:  false,
 		},
 		{
 			name:     "Missing endpoint",
 			endpoint: "",
 			region:   "us-east-1",
+			backend:  &neptuneBackend{},
 			wantErr:  true,
 			errMsg:   "Missing Neptune endpoint",
 		},
@@ -132,6 +161,7 @@ func TestValidateConfig(t *testing.T) {
 			name:     "Missing region",
 			endpoint: "wss://test.neptune.amazonaws.com:8182/gremlin",
 			region:   "",
+			backend:  &neptuneBackend{},
 			wantErr:  true,
 			errMsg:   "Missing AWS region",
 		},
@@ -139,9 +169,17 @@ func TestValidateConfig(t *testing.T) {
 			name:     "Missing both",
 			endpoint: "",
 			region:   "",
+			backend:  &neptuneBackend{},
 			wantErr:  true,
 			errMsg:   "Missing Neptune endpoint",
 		},
+		{
+			name:     "TinkerGraph backend does not require a region",
+			endpoint: "ws://localhost:8182/gremlin",
+			region:   "",
+			backend:  &tinkerGraphBackend{},
+			wantErr:  false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -149,6 +187,7 @@ func TestValidateConfig(t *testing.T) {
 			g := &Graph{
 				endpoint: tt.endpoint,
 				region:   tt.region,
+				backend:  tt.backend,
 			}
 			err := g.validateConfig()
 			if (err != nil) != tt.wantErr {
@@ -161,6 +200,44 @@ func TestValidateConfig(t *testing.T) {
 	}
 }
 
+// TestTinkerGraphBackend exercises the tinkergraph backend against a real
+// local gremlin-server, giving it actual write/query coverage instead of
+// asserting on a network failure. It skips when no server is reachable at
+// tinkerGraphTestEndpoint, so it passes offline and in CI without a
+// TinkerGraph dependency, but runs for real wherever one is available,
+// e.g. `docker run -p 8182:8182 tinkerpop/gremlin-server`.
+func TestTinkerGraphBackend(t *testing.T) {
+	conn, err := net.DialTimeout("tcp", "localhost:8182", 200*time.Millisecond)
+	if err != nil {
+		t.Skipf("no local TinkerGraph gremlin-server at %s, skipping: %v", tinkerGraphTestEndpoint, err)
+	}
+	conn.Close()
+
+	g := &Graph{}
+	c := &config.Config{}
+	c.Handler.Graph = config.Graph{
+		Endpoint: tinkerGraphTestEndpoint,
+		Backend:  string(BackendTinkerGraph),
+	}
+	if err := g.Init(c); err != nil {
+		t.Fatalf("Init() against local TinkerGraph server failed: %v", err)
+	}
+	defer g.Close()
+
+	label := fmt.Sprintf("kubewatch_test_%d", time.Now().UnixNano())
+	if _, err := g.client.Submit(fmt.Sprintf(`g.addV('%s').property('probe', true)`, label)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	result, err := g.Query(context.Background(), Gremlin, fmt.Sprintf(`g.V().hasLabel('%s').count()`, label), nil)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(result.Rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(result.Rows))
+	}
+}
+
 func TestGraphDefaults(t *testing.T) {
 	g := &Graph{}
 	c := &config.Config{}
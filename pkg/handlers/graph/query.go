@@ -0,0 +1,146 @@
+/*
+Copyright 2025 Kubewatch Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// QueryLanguage identifies which graph query language a statement is
+// written in. Neptune accepts all three over the same cluster.
+type QueryLanguage string
+
+const (
+	// Gremlin is Apache TinkerPop's imperative traversal language.
+	Gremlin QueryLanguage = "gremlin"
+	// OpenCypher is the declarative, Cypher-compatible query language.
+	OpenCypher QueryLanguage = "opencypher"
+	// SPARQL is the W3C RDF query language.
+	SPARQL QueryLanguage = "sparql"
+)
+
+// ResultSet is the language-agnostic result of a Query call.
+type ResultSet struct {
+	Language  QueryLanguage            `json:"language"`
+	Statement string                   `json:"statement"`
+	Rows      []map[string]interface{} `json:"rows"`
+}
+
+// Query runs statement, written in lang, against the graph and returns its
+// rows. Gremlin statements are submitted as script strings through the
+// handler's Gremlin client; openCypher and SPARQL are submitted over Neptune's dedicated
+// HTTP query endpoints, since they are not part of the Gremlin wire
+// protocol. bindings parameterizes the Gremlin statement (ignored for
+// openCypher/SPARQL); pass nil if statement has none.
+func (g *Graph) Query(ctx context.Context, lang QueryLanguage, statement string, bindings map[string]interface{}) (*ResultSet, error) {
+	if g.g == nil {
+		return nil, fmt.Errorf("graph handler not properly initialized")
+	}
+
+	switch lang {
+	case Gremlin, "":
+		return g.queryGremlin(ctx, statement, bindings)
+	case OpenCypher, SPARQL:
+		return g.queryHTTP(ctx, lang, statement)
+	default:
+		return nil, fmt.Errorf("unsupported query language: %s", lang)
+	}
+}
+
+// queryGremlin submits a raw Gremlin traversal string over the existing
+// Gremlin client and normalizes the response into a ResultSet.
+// bindings, when non-empty, are sent alongside statement so callers can
+// parameterize untrusted values instead of interpolating them into the
+// script.
+func (g *Graph) queryGremlin(ctx context.Context, statement string, bindings map[string]interface{}) (*ResultSet, error) {
+	g.connMu.RLock()
+	client := g.client
+	g.connMu.RUnlock()
+
+	var resultSet gremlingo.ResultSet
+	var err error
+	if len(bindings) > 0 {
+		requestOptions := gremlingo.NewRequestOptionsBuilder().SetBindings(bindings).Create()
+		resultSet, err = client.SubmitWithOptions(statement, requestOptions)
+	} else {
+		resultSet, err = client.Submit(statement)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gremlin query failed: %w", err)
+	}
+
+	rows := make([]map[string]interface{}, 0)
+	for _, result := range resultSet.All() {
+		rows = append(rows, map[string]interface{}{"result": result.GetString()})
+	}
+
+	return &ResultSet{Language: Gremlin, Statement: statement, Rows: rows}, nil
+}
+
+// queryHTTP submits statement to Neptune's openCypher or SPARQL HTTP
+// endpoint, derived from the configured Gremlin websocket endpoint.
+func (g *Graph) queryHTTP(ctx context.Context, lang QueryLanguage, statement string) (*ResultSet, error) {
+	rows, err := submitHTTPQuery(ctx, g.backend, g.endpoint, lang, statement, g.tlsSkip)
+	if err != nil {
+		return nil, err
+	}
+	return &ResultSet{Language: lang, Statement: statement, Rows: rows}, nil
+}
+
+// mutatingGremlinSteps are Gremlin steps that write to the graph. They
+// back IsReadOnlyGremlin's denylist.
+var mutatingGremlinSteps = []string{
+	"addV(", "addE(", "mergeV(", "mergeE(", "drop(", ".property(", "sideEffect(",
+}
+
+// IsReadOnlyGremlin reports whether statement contains no recognizable
+// graph-mutating step. It is a conservative denylist, not a Groovy
+// parser: it exists to stop casual misuse of the HTTP query endpoint on
+// the metrics listener (see pkg/client/run.go), not to sandbox arbitrary
+// Gremlin. The --statement CLI flag does not apply this check, since it
+// already requires local operator access.
+func IsReadOnlyGremlin(statement string) bool {
+	for _, step := range mutatingGremlinSteps {
+		if strings.Contains(statement, step) {
+			return false
+		}
+	}
+	return true
+}
+
+// RecentWarningsQuery returns a canned Gremlin traversal, and its
+// bindings, that finds resources in namespace with the most recent
+// Warning events. namespace is passed as a binding rather than
+// interpolated into the script, so it can't break out of the string
+// literal.
+func RecentWarningsQuery(namespace string) (string, map[string]interface{}) {
+	return `g.V().hasLabel('Event').has('namespace', ns).has('reason', 'Warning').` +
+			`order().by('timestamp', desc).limit(20).as('event').` +
+			`in('HAS_EVENT').as('resource').select('resource', 'event')`,
+		map[string]interface{}{"ns": namespace}
+}
+
+// OwnerChainQuery returns a canned Gremlin traversal, and its bindings,
+// that walks the OWNED_BY edges from resourceID up to its root owner.
+func OwnerChainQuery(resourceID string) (string, map[string]interface{}) {
+	return `g.V(rid).repeat(out('OWNED_BY')).emit().path()`,
+		map[string]interface{}{"rid": resourceID}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 Kubewatch Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// httpQueryPath maps a query language to the Neptune HTTP query endpoint
+// path that serves it.
+var httpQueryPath = map[QueryLanguage]string{
+	OpenCypher: "/openCypher",
+	SPARQL:     "/sparql",
+}
+
+// submitHTTPQuery posts statement to the Neptune HTTP endpoint for lang,
+// deriving the https:// URL from the Gremlin wss:// endpoint configured
+// for the handler. backend signs the request in place when it requires
+// per-request authentication (e.g. Neptune IAM database auth); backends
+// that don't leave it untouched.
+func submitHTTPQuery(ctx context.Context, backend Backend, gremlinEndpoint string, lang QueryLanguage, statement string, tlsSkip bool) ([]map[string]interface{}, error) {
+	path, ok := httpQueryPath[lang]
+	if !ok {
+		return nil, fmt.Errorf("no HTTP query endpoint for language: %s", lang)
+	}
+
+	endpoint, err := httpEndpointFor(gremlinEndpoint, path)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("query", statement)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s request: %w", lang, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if err := backend.SignHTTPRequest(ctx, req); err != nil {
+		return nil, fmt.Errorf("failed to sign %s request: %w", lang, err)
+	}
+
+	client := &http.Client{}
+	if tlsSkip {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s query failed: %w", lang, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s query returned status %d", lang, resp.StatusCode)
+	}
+
+	if lang == SPARQL {
+		return decodeSPARQLResponse(resp.Body)
+	}
+
+	var body struct {
+		Results []map[string]interface{} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", lang, err)
+	}
+
+	return body.Results, nil
+}
+
+// decodeSPARQLResponse decodes a Neptune SPARQL query response, which
+// follows the standard SPARQL 1.1 JSON Results format
+// (https://www.w3.org/TR/sparql11-results-json/): "results" is an object
+// wrapping a "bindings" array, not a top-level array as openCypher
+// returns.
+func decodeSPARQLResponse(r io.Reader) ([]map[string]interface{}, error) {
+	var body struct {
+		Results struct {
+			Bindings []map[string]interface{} `json:"bindings"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode sparql response: %w", err)
+	}
+
+	return body.Results.Bindings, nil
+}
+
+// httpEndpointFor rewrites a wss:// Gremlin endpoint into an https://
+// endpoint pointing at the given query path, e.g.
+// wss://host:8182/gremlin -> https://host:8182/openCypher.
+func httpEndpointFor(gremlinEndpoint, path string) (string, error) {
+	u, err := url.Parse(gremlinEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint %q: %w", gremlinEndpoint, err)
+	}
+
+	switch u.Scheme {
+	case "wss":
+		u.Scheme = "https"
+	case "ws":
+		u.Scheme = "http"
+	}
+	u.Path = path
+
+	return u.String(), nil
+}
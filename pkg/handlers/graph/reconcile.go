@@ -0,0 +1,161 @@
+/*
+Copyright 2025 Kubewatch Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/sirupsen/logrus"
+)
+
+// LiveResourceIDsFunc returns the "id" property (kind:namespace:name) of
+// every Resource vertex that currently exists in the cluster, as reported
+// by the caller. Reconcile calls it on every tick to find
+// vertices that should be tombstoned.
+type LiveResourceIDsFunc func(ctx context.Context) ([]string, error)
+
+// Reconcile runs a background loop that tombstones Resource vertices no
+// longer present in the cluster (per liveIDs) by setting a deletedAt
+// property, then hard-deletes vertices that have been tombstoned for
+// longer than ttl. It returns a cancel func that stops the loop; the
+// caller (pkg/client.startGraphReconcile, which lists live objects through
+// the Kubernetes dynamic client) owns the returned func's lifetime.
+func (g *Graph) Reconcile(ctx context.Context, interval, ttl time.Duration, liveIDs LiveResourceIDsFunc) context.CancelFunc {
+	ctx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := g.reconcileOnce(ctx, ttl, liveIDs); err != nil {
+					logrus.Errorf("Graph reconcile failed: %v", err)
+				}
+			}
+		}
+	}()
+
+	return cancel
+}
+
+// reconcileOnce performs a single tombstone-then-prune pass.
+func (g *Graph) reconcileOnce(ctx context.Context, ttl time.Duration, liveIDs LiveResourceIDsFunc) error {
+	ids, err := liveIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	live := make([]interface{}, len(ids))
+	for i, id := range ids {
+		live[i] = id
+	}
+
+	now := time.Now().Unix()
+	tombstoned, err := g.g.V().HasLabel("Resource").HasNot("deletedAt").
+		Not(gremlingo.T__.HasId(live...)).
+		Property("deletedAt", now).
+		Count().Next()
+	if err != nil {
+		return err
+	}
+	if n, err := tombstoned.GetInt(); err == nil && n > 0 {
+		logrus.Printf("Tombstoned %d resource vertices no longer present in the cluster", n)
+	}
+
+	cutoff := time.Now().Add(-ttl).Unix()
+	dropped, err := g.g.V().HasLabel("Resource").Has("deletedAt", gremlingo.P.Lt(cutoff)).
+		SideEffect(gremlingo.T__.Drop()).
+		Count().Next()
+	if err != nil {
+		return err
+	}
+	if n, err := dropped.GetInt(); err == nil && n > 0 {
+		logrus.Printf("Hard-deleted %d resource vertices tombstoned more than %s ago", n, ttl)
+	}
+
+	return nil
+}
+
+// KindNamespace identifies a distinct Kubernetes kind and namespace seen
+// among Resource vertices.
+type KindNamespace struct {
+	Kind      string
+	Namespace string
+}
+
+// ResourceKindNamespaces returns every distinct (kind, namespace) pair
+// currently present among non-tombstoned Resource vertices. A
+// LiveResourceIDsFunc backed by the Kubernetes API (see
+// pkg/client.newClusterLiveResourceIDs) uses it to know which kinds and
+// namespaces it actually needs to list, rather than every kind kubewatch
+// could possibly see.
+func (g *Graph) ResourceKindNamespaces(ctx context.Context) ([]KindNamespace, error) {
+	resultSet, err := g.client.Submit(
+		`g.V().hasLabel('Resource').hasNot('deletedAt').` +
+			`project('kind', 'namespace').by('kind').by('namespace').dedup()`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list distinct resource kinds: %w", err)
+	}
+
+	var pairs []KindNamespace
+	for _, result := range resultSet.All() {
+		m, ok := result.GetInterface().(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := m["kind"].(string)
+		namespace, _ := m["namespace"].(string)
+		if kind == "" {
+			continue
+		}
+		pairs = append(pairs, KindNamespace{Kind: kind, Namespace: namespace})
+	}
+	return pairs, nil
+}
+
+// ResourceIDs returns the "id" property of every non-tombstoned Resource
+// vertex of kind in namespace. newClusterLiveResourceIDs uses it to keep
+// a (kind, namespace) pair it couldn't list from the cluster this tick
+// (e.g. a transient discovery failure) out of the tombstone pass
+// entirely, rather than have every vertex of that kind look deleted
+// simply because it's missing from live.
+func (g *Graph) ResourceIDs(ctx context.Context, kind, namespace string) ([]string, error) {
+	requestOptions := gremlingo.NewRequestOptionsBuilder().
+		SetBindings(map[string]interface{}{"kind": kind, "ns": namespace}).
+		Create()
+	resultSet, err := g.client.SubmitWithOptions(
+		`g.V().hasLabel('Resource').has('kind', kind).has('namespace', ns).hasNot('deletedAt').values('id')`,
+		requestOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource ids for kind %s in namespace %s: %w", kind, namespace, err)
+	}
+
+	var ids []string
+	for _, result := range resultSet.All() {
+		if id, err := result.GetString(); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
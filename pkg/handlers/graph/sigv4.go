@@ -0,0 +1,133 @@
+/*
+Copyright 2025 Kubewatch Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// neptuneServiceName is the SigV4 service name Neptune's IAM database
+// authentication expects requests to be signed for.
+const neptuneServiceName = "neptune-db"
+
+// neptuneIAMSigner computes SigV4 signatures for the synthetic GET
+// /gremlin request Neptune's IAM database authentication checks on every
+// WebSocket handshake. It resolves credentials through the standard AWS
+// SDK v2 chain, so it picks up IRSA, an instance profile, or
+// KW_GRAPH_REGION/static environment credentials transparently.
+type neptuneIAMSigner struct {
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+}
+
+// newNeptuneIAMSigner resolves the AWS credential chain for region. It is
+// called once, at Graph.Init time; the resulting signer recomputes
+// signatures on every call to Headers so they stay valid across
+// reconnects.
+func newNeptuneIAMSigner(region string) (*neptuneIAMSigner, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS credential chain: %w", err)
+	}
+
+	return &neptuneIAMSigner{
+		region:      region,
+		credentials: cfg.Credentials,
+		signer:      v4.NewSigner(),
+	}, nil
+}
+
+// Headers signs a synthetic "GET /gremlin" request against endpoint and
+// returns the resulting Authorization, X-Amz-Date, Host, and (for
+// temporary credentials) X-Amz-Security-Token headers to send on the
+// WebSocket upgrade.
+func (s *neptuneIAMSigner) Headers(ctx context.Context, endpoint string) (http.Header, error) {
+	creds, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+
+	httpScheme := "https"
+	if u.Scheme == "ws" {
+		httpScheme = "http"
+	}
+	path := u.Path
+	if path == "" {
+		path = "/gremlin"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s%s", httpScheme, u.Host, path), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build signing request: %w", err)
+	}
+
+	emptyPayloadHash := sha256.Sum256(nil)
+	if err := s.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(emptyPayloadHash[:]), neptuneServiceName, s.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	headers := req.Header.Clone()
+	headers.Set("Host", u.Host)
+	return headers, nil
+}
+
+// Sign signs req in place with SigV4, so it can be used on any outgoing
+// request to the backend's HTTP endpoints, not just the synthetic GET
+// Headers builds for the WebSocket handshake. Unlike that GET, req may
+// carry a body (e.g. an openCypher/SPARQL query), so Sign reads it to
+// compute the real payload hash SigV4 requires, then restores it so the
+// caller can still send it.
+func (s *neptuneIAMSigner) Sign(ctx context.Context, req *http.Request) error {
+	creds, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read request body for signing: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+
+	payloadHash := sha256.Sum256(body)
+	if err := s.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), neptuneServiceName, s.region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+	return nil
+}
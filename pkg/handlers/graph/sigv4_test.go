@@ -0,0 +1,169 @@
+/*
+Copyright 2025 Kubewatch Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// fakeCredentialsProvider stands in for STS or an instance profile,
+// returning fixed static credentials so signing can be tested without
+// reaching AWS.
+type fakeCredentialsProvider struct {
+	creds aws.Credentials
+}
+
+func (p *fakeCredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	return p.creds, nil
+}
+
+func TestNeptuneIAMSignerHeaders(t *testing.T) {
+	signer := &neptuneIAMSigner{
+		region: "us-east-1",
+		credentials: &fakeCredentialsProvider{creds: aws.Credentials{
+			AccessKeyID:     "AKIAFAKEEXAMPLE",
+			SecretAccessKey: "fakeSecretKeyExample",
+			SessionToken:    "fakeSessionTokenExample",
+		}},
+		signer: v4.NewSigner(),
+	}
+
+	headers, err := signer.Headers(context.Background(), "wss://test.cluster.us-east-1.neptune.amazonaws.com:8182/gremlin")
+	if err != nil {
+		t.Fatalf("Headers() error = %v", err)
+	}
+
+	if got := headers.Get("Host"); got != "test.cluster.us-east-1.neptune.amazonaws.com:8182" {
+		t.Errorf("Host header = %q, want %q", got, "test.cluster.us-east-1.neptune.amazonaws.com:8182")
+	}
+
+	auth := headers.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 ") {
+		t.Errorf("Authorization header = %q, want AWS4-HMAC-SHA256 prefix", auth)
+	}
+	if !strings.Contains(auth, "Credential=AKIAFAKEEXAMPLE/") {
+		t.Errorf("Authorization header = %q, want it to reference the access key", auth)
+	}
+	if !strings.Contains(auth, "neptune-db/aws4_request") {
+		t.Errorf("Authorization header = %q, want it scoped to the neptune-db service", auth)
+	}
+
+	if headers.Get("X-Amz-Date") == "" {
+		t.Error("X-Amz-Date header is empty")
+	}
+	if headers.Get("X-Amz-Security-Token") != "fakeSessionTokenExample" {
+		t.Errorf("X-Amz-Security-Token header = %q, want %q", headers.Get("X-Amz-Security-Token"), "fakeSessionTokenExample")
+	}
+}
+
+func TestNeptuneIAMSignerHeadersRefreshOnEachCall(t *testing.T) {
+	signer := &neptuneIAMSigner{
+		region: "us-east-1",
+		credentials: &fakeCredentialsProvider{creds: aws.Credentials{
+			AccessKeyID:     "AKIAFAKEEXAMPLE",
+			SecretAccessKey: "fakeSecretKeyExample",
+		}},
+		signer: v4.NewSigner(),
+	}
+
+	first, err := signer.Headers(context.Background(), "wss://test.cluster.us-east-1.neptune.amazonaws.com:8182/gremlin")
+	if err != nil {
+		t.Fatalf("Headers() error = %v", err)
+	}
+
+	time.Sleep(time.Second)
+
+	second, err := signer.Headers(context.Background(), "wss://test.cluster.us-east-1.neptune.amazonaws.com:8182/gremlin")
+	if err != nil {
+		t.Fatalf("Headers() error = %v", err)
+	}
+
+	if first.Get("X-Amz-Date") == second.Get("X-Amz-Date") {
+		t.Error("expected a fresh signature (different X-Amz-Date) on each call, got the same one")
+	}
+}
+
+func TestNeptuneIAMSignerSignPreservesBody(t *testing.T) {
+	signer := &neptuneIAMSigner{
+		region: "us-east-1",
+		credentials: &fakeCredentialsProvider{creds: aws.Credentials{
+			AccessKeyID:     "AKIAFAKEEXAMPLE",
+			SecretAccessKey: "fakeSecretKeyExample",
+		}},
+		signer: v4.NewSigner(),
+	}
+
+	req, err := http.NewRequest(http.MethodPost,
+		"https://test.cluster.us-east-1.neptune.amazonaws.com:8182/openCypher",
+		strings.NewReader("query=MATCH+(n)+RETURN+n"))
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+
+	if err := signer.Sign(context.Background(), req); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 ") {
+		t.Errorf("Authorization header = %q, want AWS4-HMAC-SHA256 prefix", auth)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("failed to read signed request body: %v", err)
+	}
+	if string(body) != "query=MATCH+(n)+RETURN+n" {
+		t.Errorf("request body after signing = %q, want it unchanged", body)
+	}
+}
+
+func TestNeptuneBackendHeadersWithoutIAMAuth(t *testing.T) {
+	b := &neptuneBackend{}
+
+	headers, err := b.Headers(context.Background(), "wss://test.cluster.us-east-1.neptune.amazonaws.com:8182/gremlin")
+	if err != nil {
+		t.Fatalf("Headers() error = %v", err)
+	}
+	if headers != nil {
+		t.Errorf("Headers() = %v, want nil when IAM auth is disabled", headers)
+	}
+}
+
+func TestNeptuneBackendSignHTTPRequestWithoutIAMAuth(t *testing.T) {
+	b := &neptuneBackend{}
+
+	req, err := http.NewRequest(http.MethodPost, "https://test.cluster.us-east-1.neptune.amazonaws.com:8182/openCypher", nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+
+	if err := b.SignHTTPRequest(context.Background(), req); err != nil {
+		t.Fatalf("SignHTTPRequest() error = %v", err)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Errorf("Authorization header = %q, want empty when IAM auth is disabled", req.Header.Get("Authorization"))
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2025 Kubewatch Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"fmt"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+
+	"github.com/yindia/kubewatch/pkg/event"
+)
+
+// Edge labels used to materialize cluster topology, as distinct from the
+// generic HAS_EVENT edge linking a resource to the events observed on it.
+const (
+	EdgeOwnedBy     = "OWNED_BY"
+	EdgeScheduledOn = "SCHEDULED_ON"
+	EdgeSelects     = "SELECTS"
+	EdgeMounts      = "MOUNTS"
+	EdgeInNamespace = "IN_NAMESPACE"
+)
+
+// namespaceVertexID and nodeVertexID key cluster-scoped vertices, which
+// are not identified the same way as namespaced Resource vertices.
+func namespaceVertexID(namespace string) string {
+	return fmt.Sprintf("Namespace::%s", namespace)
+}
+
+func nodeVertexID(nodeName string) string {
+	return fmt.Sprintf("Node::%s", nodeName)
+}
+
+func persistentVolumeVertexID(pvName string) string {
+	return fmt.Sprintf("PersistentVolume::%s", pvName)
+}
+
+// appendTopologyEdges chains the topology edges derivable from a single
+// event onto traversal: namespace membership, owner references, pod
+// scheduling, service selection, and volume mounts. Each edge is upserted
+// so repeated events for the same relationship are idempotent.
+func (g *Graph) appendTopologyEdges(traversal *gremlingo.GraphTraversal, resourceID string, e event.Event) *gremlingo.GraphTraversal {
+	now := time.Now().Unix()
+
+	if e.Namespace != "" && e.Kind != "Namespace" {
+		nsID := namespaceVertexID(e.Namespace)
+		traversal = traversal.SideEffect(
+			gremlingo.T__.Coalesce(
+				gremlingo.T__.V(nsID),
+				g.decorateVertex(gremlingo.T__.AddV("Namespace").Property("id", nsID).Property("name", e.Namespace)),
+			),
+		)
+		traversal = traversal.SideEffect(upsertEdgeStep(resourceID, EdgeInNamespace, nsID, now))
+	}
+
+	for _, owner := range e.OwnerReferences {
+		ownerID := fmt.Sprintf("%s:%s:%s", owner.Kind, e.Namespace, owner.Name)
+		traversal = traversal.SideEffect(
+			gremlingo.T__.Coalesce(
+				gremlingo.T__.V(ownerID),
+				g.decorateVertex(gremlingo.T__.AddV("Resource").
+					Property("id", ownerID).
+					Property("kind", owner.Kind).
+					Property("name", owner.Name).
+					Property("namespace", e.Namespace).
+					Property("createdAt", now).
+					Property("lastUpdated", now)),
+			),
+		)
+		traversal = traversal.SideEffect(upsertEdgeStep(resourceID, EdgeOwnedBy, ownerID, now))
+	}
+
+	if e.Kind == "Pod" && e.NodeName != "" {
+		nodeID := nodeVertexID(e.NodeName)
+		traversal = traversal.SideEffect(
+			gremlingo.T__.Coalesce(
+				gremlingo.T__.V(nodeID),
+				g.decorateVertex(gremlingo.T__.AddV("Node").Property("id", nodeID).Property("name", e.NodeName)),
+			),
+		)
+		traversal = traversal.SideEffect(upsertEdgeStep(resourceID, EdgeScheduledOn, nodeID, now))
+	}
+
+	if e.Kind == "Service" {
+		for _, podName := range e.SelectedPods {
+			podID := fmt.Sprintf("Pod:%s:%s", e.Namespace, podName)
+			traversal = traversal.SideEffect(
+				gremlingo.T__.Coalesce(
+					gremlingo.T__.V(podID),
+					g.decorateVertex(gremlingo.T__.AddV("Resource").
+						Property("id", podID).
+						Property("kind", "Pod").
+						Property("name", podName).
+						Property("namespace", e.Namespace).
+						Property("createdAt", now).
+						Property("lastUpdated", now)),
+				),
+			)
+			traversal = traversal.SideEffect(upsertEdgeStep(resourceID, EdgeSelects, podID, now))
+		}
+	}
+
+	if e.Kind == "Pod" {
+		for _, pvcName := range e.VolumeClaims {
+			pvcID := fmt.Sprintf("PersistentVolumeClaim:%s:%s", e.Namespace, pvcName)
+			traversal = traversal.SideEffect(
+				gremlingo.T__.Coalesce(
+					gremlingo.T__.V(pvcID),
+					g.decorateVertex(gremlingo.T__.AddV("Resource").
+						Property("id", pvcID).
+						Property("kind", "PersistentVolumeClaim").
+						Property("name", pvcName).
+						Property("namespace", e.Namespace).
+						Property("createdAt", now).
+						Property("lastUpdated", now)),
+				),
+			)
+			traversal = traversal.SideEffect(upsertEdgeStep(resourceID, EdgeMounts, pvcID, now))
+		}
+	}
+
+	if e.Kind == "PersistentVolumeClaim" && e.VolumeName != "" {
+		pvID := persistentVolumeVertexID(e.VolumeName)
+		traversal = traversal.SideEffect(
+			gremlingo.T__.Coalesce(
+				gremlingo.T__.V(pvID),
+				g.decorateVertex(gremlingo.T__.AddV("PersistentVolume").Property("id", pvID).Property("name", e.VolumeName)),
+			),
+		)
+		traversal = traversal.SideEffect(upsertEdgeStep(resourceID, EdgeMounts, pvID, now))
+	}
+
+	return traversal
+}
+
+// upsertEdgeStep returns an anonymous traversal that adds a labeled edge
+// from fromID to toID unless one already exists, refreshing its "since"
+// timestamp either way.
+func upsertEdgeStep(fromID, label, toID string, now int64) *gremlingo.GraphTraversal {
+	return gremlingo.T__.Coalesce(
+		gremlingo.T__.V(fromID).OutE(label).Where(gremlingo.T__.InV().HasId(toID)).
+			Property("since", now),
+		gremlingo.T__.V(fromID).AddE(label).To(gremlingo.T__.V(toID)).
+			Property("since", now),
+	)
+}